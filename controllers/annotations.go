@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// projectAnnotation, when set on a CAPI Cluster, is copied into the
+	// ArgoSecret's "project" field, scoping the cluster to an Argo
+	// AppProject.
+	projectAnnotation = "capi-to-argocd/project"
+
+	// namespacesAnnotation, when set on a CAPI Cluster, is a comma-separated
+	// list copied into the ArgoSecret's "namespaces" field.
+	namespacesAnnotation = "capi-to-argocd/namespaces"
+
+	// clusterResourcesAnnotation, when set to a parseable bool on a CAPI
+	// Cluster, is copied into the ArgoSecret's "clusterResources" field.
+	clusterResourcesAnnotation = "capi-to-argocd/cluster-resources"
+)
+
+// argoLabelPrefix selects which labels on the CAPI Cluster are copied onto
+// the generated ArgoSecret. Configurable via ARGOCD_LABEL_PREFIX (default
+// "argocd.argoproj.io/").
+var argoLabelPrefix string
+
+func init() {
+	argoLabelPrefix = os.Getenv("ARGOCD_LABEL_PREFIX")
+	if argoLabelPrefix == "" {
+		argoLabelPrefix = "argocd.argoproj.io/"
+	}
+}
+
+// applyClusterAnnotations translates capi-to-argocd/* annotations on cluster
+// into Argo cluster-secret fields on argoSecret, and copies any Cluster
+// label prefixed with argoLabelPrefix onto the Secret's labels. It lets
+// operators scope a newly-registered cluster to an AppProject and namespace
+// list without editing the generated Secret by hand.
+func applyClusterAnnotations(cluster *clusterv1.Cluster, argoSecret *corev1.Secret) {
+	annotations := cluster.GetAnnotations()
+
+	if project, ok := annotations[projectAnnotation]; ok {
+		argoSecret.Data["project"] = []byte(project)
+	}
+
+	if namespaces, ok := annotations[namespacesAnnotation]; ok {
+		argoSecret.Data["namespaces"] = []byte(strings.Join(splitAndTrim(namespaces), ","))
+	}
+
+	if raw, ok := annotations[clusterResourcesAnnotation]; ok {
+		if clusterResources, err := strconv.ParseBool(raw); err == nil {
+			argoSecret.Data["clusterResources"] = []byte(strconv.FormatBool(clusterResources))
+		}
+	}
+
+	for key, value := range cluster.GetLabels() {
+		if strings.HasPrefix(key, argoLabelPrefix) {
+			argoSecret.Labels[key] = value
+		}
+	}
+}
+
+// splitAndTrim splits a comma-separated list and trims whitespace from each
+// element, dropping empty entries.
+func splitAndTrim(raw string) []string {
+	parts := strings.Split(raw, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
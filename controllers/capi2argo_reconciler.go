@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -14,7 +16,27 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/cluster-api/util"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// controlPlaneNotReadyRequeueInterval is how long Reconcile waits before
+	// re-checking a CAPI cluster whose control-plane isn't ready yet.
+	controlPlaneNotReadyRequeueInterval = 30 * time.Second
+
+	// capiFinalizer is added to every CAPI kubeconfig Secret CACO manages, so
+	// its derived ArgoSecret can be cleaned up before the Secret is removed.
+	capiFinalizer = "capi-to-argocd/finalizer"
+
+	// sourceSecretAnnotation records the namespace/name of the CAPI
+	// kubeconfig Secret an ArgoSecret was derived from, so a reverse lookup
+	// (janitor, drift scheduler) doesn't need to re-derive the name.
+	sourceSecretAnnotation = "capi-to-argocd/source-secret"
 )
 
 func init() {
@@ -26,11 +48,28 @@ func init() {
 	}
 
 	EnableNamespacedNames, _ = strconv.ParseBool(os.Getenv("ENABLE_NAMESPACED_NAMES"))
+
+	RequireControlPlaneReady = true
+	if v, ok := os.LookupEnv("REQUIRE_CONTROLPLANE_READY"); ok {
+		RequireControlPlaneReady, _ = strconv.ParseBool(v)
+	}
 }
 
+// RequireControlPlaneReady gates ArgoSecret creation/updates behind the CAPI
+// cluster's control-plane becoming ready, so Argo never registers a cluster
+// it can't yet reach. Configurable via REQUIRE_CONTROLPLANE_READY (default
+// true).
+var RequireControlPlaneReady bool
+
 // Capi2Argo reconciles a Secret object
 type Capi2Argo struct {
 	client.Client
+
+	// APIReader performs uncached Get/List calls for lookups that
+	// shouldn't grow the manager cache, such as the one-off argocd-cm probe
+	// in warnIfArgoCDMissing.
+	APIReader client.Reader
+
 	Log    logr.Logger
 	Scheme *runtime.Scheme
 }
@@ -42,8 +81,6 @@ type Capi2Argo struct {
 func (r *Capi2Argo) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithValues("secret", req.NamespacedName)
 
-	// TODO: Check if secret is on allowed Namespaces.
-
 	// Validate Secret.Metadata.Name complies with CAPI pattern: <clusterName>-kubeconfig
 	if !ValidateCapiNaming(req.NamespacedName) {
 		return ctrl.Result{}, nil
@@ -57,6 +94,12 @@ func (r *Capi2Argo) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resul
 	}
 	log.Info("Fetched CapiSecret")
 
+	// CapiSecret is being deleted: clean up the ArgoSecret it owns, then
+	// release the finalizer so deletion can proceed.
+	if !capiSecret.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, log, &capiSecret)
+	}
+
 	// Validate CapiSecret.type is matching CAPI convention.
 	// if capiSecret.Type != "cluster.x-k8s.io/secret" {
 	err = ValidateCapiSecret(&capiSecret)
@@ -65,12 +108,30 @@ func (r *Capi2Argo) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resul
 		return ctrl.Result{}, err
 	}
 
+	// Make sure we get a chance to clean up the ArgoSecret once this Secret
+	// (and the CAPI cluster it belongs to) is deleted.
+	if !controllerutil.ContainsFinalizer(&capiSecret, capiFinalizer) {
+		controllerutil.AddFinalizer(&capiSecret, capiFinalizer)
+		if err := r.Update(ctx, &capiSecret); err != nil {
+			log.Error(err, "Failed to add finalizer to CapiSecret")
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Fetch CAPI cluster object
 	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, capiSecret.ObjectMeta)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to get cluster object from secret: %w", err)
 	}
 
+	// Don't register the cluster with Argo before its control-plane is
+	// reachable, otherwise Argo flips it to "unknown" until CAPI finishes
+	// bootstrapping.
+	if RequireControlPlaneReady && !cluster.Status.ControlPlaneReady {
+		log.Info("Waiting for CAPI control-plane to become ready", "cluster", cluster.Name)
+		return ctrl.Result{RequeueAfter: controlPlaneNotReadyRequeueInterval}, nil
+	}
+
 	// Construct CapiCluster from CapiSecret.
 	capiCluster := NewCapiCluster()
 	err = capiCluster.Unmarshal(&capiSecret)
@@ -82,6 +143,33 @@ func (r *Capi2Argo) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resul
 	// Construct ArgoCluster from CapiCluster and CapiSecret.Metadata.
 	argoCluster := NewArgoCluster(capiCluster, &capiSecret)
 
+	// Route to a per-cluster ArgoCD namespace when the Cluster requests one,
+	// falling back to ArgoNamespace otherwise.
+	if ns, ok := cluster.GetAnnotations()[argoNamespaceAnnotation]; ok && ns != "" {
+		argoCluster.NamespacedName.Namespace = ns
+	}
+
+	if !isAllowedArgoNamespace(argoCluster.NamespacedName.Namespace) {
+		log.Info("Refusing to write ArgoSecret into namespace outside ARGOCD_NAMESPACES allow-list", "namespace", argoCluster.NamespacedName.Namespace)
+		return ctrl.Result{}, nil
+	}
+
+	r.warnIfArgoCDMissing(ctx, log, argoCluster.NamespacedName.Namespace)
+
+	// Remember where we routed the ArgoSecret on the CapiSecret itself, so
+	// reconcileDelete can still find it once the CAPI Cluster object (and
+	// argoNamespaceAnnotation along with it) is gone.
+	if capiSecret.Annotations[resolvedNamespaceAnnotation] != argoCluster.NamespacedName.Namespace {
+		if capiSecret.Annotations == nil {
+			capiSecret.Annotations = map[string]string{}
+		}
+		capiSecret.Annotations[resolvedNamespaceAnnotation] = argoCluster.NamespacedName.Namespace
+		if err := r.Update(ctx, &capiSecret); err != nil {
+			log.Error(err, "Failed to record resolved ArgoCD namespace on CapiSecret")
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Convert ArgoCluster into ArgoSecret to work natively on k8s objects.
 	log = r.Log.WithValues("cluster", argoCluster.NamespacedName)
 	argoSecret, err := argoCluster.ConvertToSecret()
@@ -95,6 +183,19 @@ func (r *Capi2Argo) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resul
 		argoSecret.Labels["class"] = cluster.Spec.Topology.Class
 	}
 
+	// Translate capi-to-argocd/* annotations on the Cluster into Argo
+	// project/namespaces/clusterResources fields, and copy Argo-prefixed
+	// labels across.
+	applyClusterAnnotations(cluster, argoSecret)
+
+	// Record which CAPI kubeconfig Secret this ArgoSecret was derived from,
+	// so the janitor and drift scheduler can find it again without
+	// re-deriving the cluster name.
+	if argoSecret.Annotations == nil {
+		argoSecret.Annotations = map[string]string{}
+	}
+	argoSecret.Annotations[sourceSecretAnnotation] = req.NamespacedName.String()
+
 	// Represent a possible existing ArgoSecret.
 	var existingSecret corev1.Secret
 	var exists bool
@@ -153,6 +254,45 @@ func (r *Capi2Argo) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resul
 			changed = true
 		}
 
+		for _, field := range []string{"project", "namespaces", "clusterResources"} {
+			if !bytes.Equal(existingSecret.Data[field], argoSecret.Data[field]) {
+				existingSecret.Data[field] = argoSecret.Data[field]
+				changed = true
+			}
+		}
+
+		// Reconcile argoLabelPrefix-prefixed labels as a full set: apply
+		// everything currently on the Cluster, and remove anything that was
+		// there before but isn't anymore, so de-scoping a cluster doesn't
+		// leave stale Argo labels behind.
+		for key := range existingSecret.Labels {
+			if !strings.HasPrefix(key, argoLabelPrefix) {
+				continue
+			}
+			if _, ok := argoSecret.Labels[key]; !ok {
+				delete(existingSecret.Labels, key)
+				changed = true
+			}
+		}
+
+		for key, value := range argoSecret.Labels {
+			if !strings.HasPrefix(key, argoLabelPrefix) {
+				continue
+			}
+			if existingSecret.Labels[key] != value {
+				existingSecret.Labels[key] = value
+				changed = true
+			}
+		}
+
+		if existingSecret.Annotations[sourceSecretAnnotation] != argoSecret.Annotations[sourceSecretAnnotation] {
+			if existingSecret.Annotations == nil {
+				existingSecret.Annotations = map[string]string{}
+			}
+			existingSecret.Annotations[sourceSecretAnnotation] = argoSecret.Annotations[sourceSecretAnnotation]
+			changed = true
+		}
+
 		if changed {
 			log.Info("Updating out-of-sync ArgoSecret")
 			if err := r.Update(ctx, &existingSecret); err != nil {
@@ -170,9 +310,58 @@ func (r *Capi2Argo) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resul
 	return ctrl.Result{}, nil
 }
 
-// SetupWithManager ..
-func (r *Capi2Argo) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).For(&corev1.Secret{}).Complete(r)
+// reconcileDelete removes the ArgoSecret derived from capiSecret, then
+// releases capiFinalizer so the CAPI kubeconfig Secret can actually be
+// deleted. It tolerates a CapiSecret that can no longer be unmarshalled
+// (e.g. the data was already scrubbed) by just releasing the finalizer.
+func (r *Capi2Argo) reconcileDelete(ctx context.Context, log logr.Logger, capiSecret *corev1.Secret) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(capiSecret, capiFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	capiCluster := NewCapiCluster()
+	if err := capiCluster.Unmarshal(capiSecret); err != nil {
+		log.Error(err, "Failed to unmarshal CapiCluster during cleanup, removing finalizer anyway")
+	} else {
+		argoCluster := NewArgoCluster(capiCluster, capiSecret)
+		if ns := capiSecret.Annotations[resolvedNamespaceAnnotation]; ns != "" {
+			argoCluster.NamespacedName.Namespace = ns
+		}
+
+		var existingSecret corev1.Secret
+		err := r.Get(ctx, argoCluster.NamespacedName, &existingSecret)
+		switch {
+		case errors.IsNotFound(err):
+			// Already gone.
+		case err != nil:
+			log.Error(err, "Failed to fetch ArgoSecret for cleanup")
+			return ctrl.Result{}, err
+		case ValidateObjectOwner(existingSecret) == nil:
+			if err := r.Delete(ctx, &existingSecret); err != nil && !errors.IsNotFound(err) {
+				log.Error(err, "Failed to delete orphaned ArgoSecret")
+				return ctrl.Result{}, err
+			}
+			log.Info("Deleted orphaned ArgoSecret", "cluster", argoCluster.NamespacedName)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(capiSecret, capiFinalizer)
+	if err := r.Update(ctx, capiSecret); err != nil {
+		log.Error(err, "Failed to remove finalizer from CapiSecret")
+		return ctrl.Result{}, err
+	}
+	log.Info("Removed finalizer from CapiSecret")
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager wires the Secret informer (its events filtered to CAPI
+// kubeconfig Secrets) together with resyncEvents, the drift scheduler's periodic
+// re-enqueue channel, so both sources feed the same workqueue.
+func (r *Capi2Argo) SetupWithManager(mgr ctrl.Manager, resyncEvents <-chan event.GenericEvent) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Secret{}, builder.WithPredicates(capiSecretPredicate)).
+		Watches(&source.Channel{Source: resyncEvents}, &handler.EnqueueRequestForObject{}).
+		Complete(r)
 }
 
 // ValidateObjectOwner checks whether reconciled object is managed by CACO or not.
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// janitorInterval controls how often ArgoSecretJanitor sweeps ArgoNamespace
+// for orphaned ArgoSecrets. Configurable via JANITOR_INTERVAL; a duration
+// <= 0 disables the janitor.
+var janitorInterval = 10 * time.Minute
+
+func init() {
+	if v := os.Getenv("JANITOR_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			janitorInterval = d
+		}
+	}
+}
+
+// ArgoSecretJanitor periodically deletes ArgoSecrets whose source CAPI
+// kubeconfig Secret no longer exists, as recorded in sourceSecretAnnotation.
+// It catches ArgoSecrets whose source was removed without reconcileDelete
+// running. ArgoSecrets without sourceSecretAnnotation are left alone, so one
+// orphaned before CACO recorded that annotation is not cleaned up. It
+// implements manager.Runnable so it runs for the lifetime of the manager.
+type ArgoSecretJanitor struct {
+	client.Client
+
+	// APIReader lists ArgoSecrets straight from the API server instead of
+	// the manager cache, so the sweep keeps working regardless of how the
+	// Secret informer is scoped (it may not cache Opaque ArgoSecrets at all).
+	APIReader client.Reader
+
+	Log logr.Logger
+}
+
+var _ manager.Runnable = &ArgoSecretJanitor{}
+
+// SetupWithManager registers the janitor to run alongside the reconciler.
+func (j *ArgoSecretJanitor) SetupWithManager(mgr manager.Manager) error {
+	return mgr.Add(j)
+}
+
+// Start blocks until ctx is cancelled, sweeping every janitorInterval. With
+// janitorInterval <= 0 it returns immediately.
+func (j *ArgoSecretJanitor) Start(ctx context.Context) error {
+	if janitorInterval <= 0 {
+		j.Log.Info("ArgoSecretJanitor disabled via JANITOR_INTERVAL")
+		return nil
+	}
+
+	ticker := time.NewTicker(janitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			j.sweep(ctx)
+		}
+	}
+}
+
+// sweep lists every ArgoSecret CACO owns, across every namespace routing
+// could have placed one in, and deletes the ones whose source CAPI
+// kubeconfig Secret is gone.
+func (j *ArgoSecretJanitor) sweep(ctx context.Context) {
+	for _, namespace := range argoTargetNamespaces() {
+		j.sweepNamespace(ctx, namespace)
+	}
+}
+
+func (j *ArgoSecretJanitor) sweepNamespace(ctx context.Context, ns string) {
+	var secrets corev1.SecretList
+	if err := j.APIReader.List(ctx, &secrets, client.InNamespace(ns), client.MatchingLabels{"capi-to-argocd/owned": "true"}); err != nil {
+		j.Log.Error(err, "Janitor failed to list ArgoSecrets", "namespace", ns)
+		return
+	}
+
+	for i := range secrets.Items {
+		argoSecret := secrets.Items[i]
+
+		source, ok := argoSecret.Annotations[sourceSecretAnnotation]
+		if !ok {
+			continue
+		}
+
+		namespace, name, ok := strings.Cut(source, "/")
+		if !ok {
+			continue
+		}
+
+		var capiSecret corev1.Secret
+		err := j.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &capiSecret)
+		if err == nil || !errors.IsNotFound(err) {
+			continue
+		}
+
+		if err := j.Delete(ctx, &argoSecret); err != nil && !errors.IsNotFound(err) {
+			j.Log.Error(err, "Janitor failed to delete orphaned ArgoSecret", "secret", client.ObjectKeyFromObject(&argoSecret))
+			continue
+		}
+		j.Log.Info("Janitor deleted orphaned ArgoSecret", "secret", client.ObjectKeyFromObject(&argoSecret))
+	}
+}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"context"
+	"os"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// argoNamespaceAnnotation, when set on a CAPI Cluster, routes its ArgoSecret
+// into that namespace instead of ArgoNamespace. This is what lets a single
+// management cluster fan CAPI clusters out to different ArgoCD tenants.
+const argoNamespaceAnnotation = "capi-to-argocd/argocd-namespace"
+
+// resolvedNamespaceAnnotation is stamped onto the CAPI kubeconfig Secret
+// with the ArgoCD namespace its ArgoSecret was actually routed to, so
+// reconcileDelete can find (and clean up) the ArgoSecret once the CAPI
+// Cluster object — and argoNamespaceAnnotation along with it — is gone.
+const resolvedNamespaceAnnotation = "capi-to-argocd/resolved-argocd-namespace"
+
+// allowedArgoNamespaces is the optional namespace allow-list configured via
+// ARGOCD_NAMESPACES. A nil set means "any namespace is allowed".
+var allowedArgoNamespaces map[string]struct{}
+
+func init() {
+	allowedArgoNamespaces = parseNamespaceList(os.Getenv("ARGOCD_NAMESPACES"))
+}
+
+// isAllowedArgoNamespace reports whether namespace is within the
+// ARGOCD_NAMESPACES allow-list, or true if no allow-list was configured.
+// ArgoNamespace (the default target for clusters with no routing
+// annotation) is always allowed, even if an operator's allow-list omits it,
+// so an "explicit allow-list" config doesn't silently stop registering
+// ordinary clusters.
+func isAllowedArgoNamespace(namespace string) bool {
+	if allowedArgoNamespaces == nil || namespace == ArgoNamespace {
+		return true
+	}
+	_, ok := allowedArgoNamespaces[namespace]
+	return ok
+}
+
+// argoTargetNamespaces returns the namespaces the janitor and drift
+// scheduler should sweep for ArgoSecrets. When ARGOCD_NAMESPACES is set, per-
+// cluster routing can only have landed in ArgoNamespace or one of those
+// namespaces (isAllowedArgoNamespace rejects anything else), so sweeping
+// just that set is exhaustive. With no allow-list configured, routing can
+// target any namespace, so the sweep has to be cluster-wide.
+func argoTargetNamespaces() []string {
+	if allowedArgoNamespaces == nil {
+		return []string{corev1.NamespaceAll}
+	}
+
+	set := map[string]struct{}{ArgoNamespace: {}}
+	for ns := range allowedArgoNamespaces {
+		set[ns] = struct{}{}
+	}
+
+	namespaces := make([]string, 0, len(set))
+	for ns := range set {
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces
+}
+
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get
+
+// warnIfArgoCDMissing logs a warning if namespace doesn't have the
+// argocd-cm ConfigMap ArgoCD installs, which usually means an operator
+// pointed CACO at a namespace that doesn't actually run ArgoCD. It reads
+// through r.APIReader instead of the manager cache, so this one-off probe
+// doesn't make the manager start and hold a cluster-wide ConfigMap informer.
+func (r *Capi2Argo) warnIfArgoCDMissing(ctx context.Context, log logr.Logger, namespace string) {
+	var cm corev1.ConfigMap
+	err := r.APIReader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "argocd-cm"}, &cm)
+	switch {
+	case errors.IsNotFound(err):
+		log.Info("Target namespace has no argocd-cm ConfigMap, is ArgoCD installed there?", "namespace", namespace)
+	case err != nil:
+		log.Info("Unable to verify ArgoCD is installed in target namespace", "namespace", namespace, "error", err.Error())
+	}
+}
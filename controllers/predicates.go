@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// watchNamespaces holds the optional namespace allow-list configured via
+// ARGOCD_WATCH_NAMESPACES. A nil/empty set means "watch every namespace".
+var watchNamespaces map[string]struct{}
+
+func init() {
+	watchNamespaces = parseNamespaceList(os.Getenv("ARGOCD_WATCH_NAMESPACES"))
+}
+
+// parseNamespaceList turns a comma-separated namespace list into a lookup
+// set, returning nil for an empty string so callers can treat nil as "no
+// restriction".
+func parseNamespaceList(raw string) map[string]struct{} {
+	if raw == "" {
+		return nil
+	}
+
+	set := make(map[string]struct{})
+	for _, ns := range strings.Split(raw, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns != "" {
+			set[ns] = struct{}{}
+		}
+	}
+	return set
+}
+
+// isCapiKubeconfigSecret reports whether obj is a Secret matching the CAPI
+// kubeconfig convention and, when ARGOCD_WATCH_NAMESPACES is set, lives in an
+// allowed namespace. It backs capiSecretPredicate so Reconcile is never
+// queued for (and never has to throw away) unrelated Secrets; the shared
+// informer itself still caches every Secret.
+func isCapiKubeconfigSecret(obj client.Object) bool {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return false
+	}
+
+	if !ValidateCapiNaming(client.ObjectKeyFromObject(secret)) {
+		return false
+	}
+
+	if err := ValidateCapiSecret(secret); err != nil {
+		return false
+	}
+
+	if len(watchNamespaces) == 0 {
+		return true
+	}
+	_, allowed := watchNamespaces[secret.Namespace]
+	return allowed
+}
+
+// capiSecretPredicate filters the Secret informer's events down to objects that match
+// the CAPI kubeconfig naming and type convention, so the controller is never
+// woken up for the thousands of unrelated Secrets a cluster typically holds.
+var capiSecretPredicate = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return isCapiKubeconfigSecret(e.Object)
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return isCapiKubeconfigSecret(e.ObjectNew)
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return isCapiKubeconfigSecret(e.Object)
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return isCapiKubeconfigSecret(e.Object)
+	},
+}
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// resyncInterval controls how often DriftScheduler re-enqueues every CAPI
+// kubeconfig Secret CACO manages, independent of the Secret informer.
+// Configurable via RESYNC_INTERVAL; a duration <= 0 disables it.
+var resyncInterval = 10 * time.Minute
+
+func init() {
+	if v := os.Getenv("RESYNC_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			resyncInterval = d
+		}
+	}
+}
+
+// DriftScheduler periodically re-enqueues every CAPI kubeconfig Secret known
+// to CACO, so a manually-edited ArgoSecret (a rotated token, a changed
+// server) gets corrected even without a new CAPI Secret event. It implements
+// manager.Runnable and feeds Reconcile through Events.
+type DriftScheduler struct {
+	client.Client
+
+	// APIReader lists ArgoSecrets straight from the API server instead of
+	// the manager cache, so the resync keeps working regardless of how the
+	// Secret informer is scoped (it may not cache Opaque ArgoSecrets at all).
+	APIReader client.Reader
+
+	Log logr.Logger
+
+	// Events is consumed by Capi2Argo.SetupWithManager as an additional
+	// event source, so a resync enqueues through the same workqueue as
+	// ordinary Secret events.
+	Events chan event.GenericEvent
+}
+
+var _ manager.Runnable = &DriftScheduler{}
+
+// SetupWithManager registers the scheduler to run alongside the reconciler.
+func (s *DriftScheduler) SetupWithManager(mgr manager.Manager) error {
+	return mgr.Add(s)
+}
+
+// Start blocks until ctx is cancelled, resyncing every resyncInterval. With
+// resyncInterval <= 0 it returns immediately.
+func (s *DriftScheduler) Start(ctx context.Context) error {
+	if resyncInterval <= 0 {
+		s.Log.Info("DriftScheduler disabled via RESYNC_INTERVAL")
+		return nil
+	}
+
+	ticker := time.NewTicker(resyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.resync(ctx)
+		}
+	}
+}
+
+// resync lists every ArgoSecret CACO owns, across every namespace routing
+// could have placed one in, and for each emits a GenericEvent carrying its
+// source CAPI kubeconfig Secret.
+func (s *DriftScheduler) resync(ctx context.Context) {
+	for _, namespace := range argoTargetNamespaces() {
+		s.resyncNamespace(ctx, namespace)
+	}
+}
+
+func (s *DriftScheduler) resyncNamespace(ctx context.Context, ns string) {
+	var secrets corev1.SecretList
+	if err := s.APIReader.List(ctx, &secrets, client.InNamespace(ns), client.MatchingLabels{"capi-to-argocd/owned": "true"}); err != nil {
+		s.Log.Error(err, "DriftScheduler failed to list ArgoSecrets", "namespace", ns)
+		return
+	}
+
+	for i := range secrets.Items {
+		source, ok := secrets.Items[i].Annotations[sourceSecretAnnotation]
+		if !ok {
+			continue
+		}
+
+		namespace, name, ok := strings.Cut(source, "/")
+		if !ok {
+			continue
+		}
+
+		var capiSecret corev1.Secret
+		if err := s.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &capiSecret); err != nil {
+			if !errors.IsNotFound(err) {
+				s.Log.Error(err, "DriftScheduler failed to fetch CapiSecret", "secret", namespace+"/"+name)
+			}
+			continue
+		}
+
+		select {
+		case s.Events <- event.GenericEvent{Object: &capiSecret}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/syself/capi2argo-cluster-operator/controllers"
+)
+
+var (
+	scheme   = runtime.NewScheme()
+	setupLog = ctrl.Log.WithName("setup")
+)
+
+func init() {
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+}
+
+func main() {
+	ctrl.SetLogger(zap.New())
+
+	// Note: we intentionally don't scope the manager cache's Secret informer
+	// to the CAPI kubeconfig type. Doing so also hides the Opaque ArgoSecrets
+	// CACO creates from every cached read, breaking the reconciler's own
+	// existence check. capiSecretPredicate (controllers/predicates.go)
+	// already keeps unrelated Secrets from triggering reconciles; that's
+	// where the cost of watching every Secret is actually cut.
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+		Scheme: scheme,
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to start manager")
+		os.Exit(1)
+	}
+
+	resyncEvents := make(chan event.GenericEvent)
+
+	reconciler := &controllers.Capi2Argo{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("controllers").WithName("Capi2Argo"),
+		Scheme:    mgr.GetScheme(),
+	}
+	if err := reconciler.SetupWithManager(mgr, resyncEvents); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Capi2Argo")
+		os.Exit(1)
+	}
+
+	scheduler := &controllers.DriftScheduler{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("controllers").WithName("DriftScheduler"),
+		Events:    resyncEvents,
+	}
+	if err := scheduler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create runnable", "runnable", "DriftScheduler")
+		os.Exit(1)
+	}
+
+	janitor := &controllers.ArgoSecretJanitor{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("controllers").WithName("ArgoSecretJanitor"),
+	}
+	if err := janitor.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create runnable", "runnable", "ArgoSecretJanitor")
+		os.Exit(1)
+	}
+
+	setupLog.Info("starting manager")
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		setupLog.Error(err, "problem running manager")
+		os.Exit(1)
+	}
+}